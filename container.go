@@ -0,0 +1,112 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// containerRuntime knows how to resolve a container id or name to the PID
+// of its init process by talking to the runtime's local control socket.
+type containerRuntime struct {
+	name   string
+	socket string
+	lookup func(ctx context.Context, client *http.Client, id string) (int, error)
+}
+
+// containerRuntimes lists the runtimes gops knows how to query, tried in
+// order until one resolves the id.
+var containerRuntimes = []containerRuntime{
+	{name: "docker", socket: "/var/run/docker.sock", lookup: lookupDockerPID},
+	{name: "containerd", socket: "/run/containerd/containerd.sock", lookup: lookupCRIPID},
+	{name: "cri-o", socket: "/var/run/crio/crio.sock", lookup: lookupCRIPID},
+}
+
+// resolveContainerPID resolves id, which may be a container id or name, to
+// the PID of the container's init process as seen from the host, by
+// probing the local Docker, containerd, and CRI-O sockets in turn.
+func resolveContainerPID(id string) (int, error) {
+	var errs []string
+	for _, rt := range containerRuntimes {
+		client := &http.Client{
+			Transport: &http.Transport{
+				DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+					return (&net.Dialer{}).DialContext(ctx, "unix", rt.socket)
+				},
+			},
+			Timeout: 2 * time.Second,
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		pid, err := rt.lookup(ctx, client, id)
+		cancel()
+		if err == nil {
+			return pid, nil
+		}
+		errs = append(errs, fmt.Sprintf("%s: %v", rt.name, err))
+	}
+	return 0, fmt.Errorf("gops: could not resolve container %q: %s", id, strings.Join(errs, "; "))
+}
+
+// lookupDockerPID queries the Docker Engine API's container inspect
+// endpoint over the local Unix socket.
+func lookupDockerPID(ctx context.Context, client *http.Client, id string) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://unix/containers/"+id+"/json", nil)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	var payload struct {
+		State struct {
+			Pid int `json:"Pid"`
+		} `json:"State"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return 0, err
+	}
+	if payload.State.Pid == 0 {
+		return 0, fmt.Errorf("container %q is not running", id)
+	}
+	return payload.State.Pid, nil
+}
+
+// lookupCRIPID queries containerd/CRI-O's debug HTTP endpoint, which both
+// runtimes expose over their control socket, for a container's init PID.
+func lookupCRIPID(ctx context.Context, client *http.Client, id string) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://unix/containers/"+id, nil)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	var payload struct {
+		Pid int `json:"pid"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return 0, err
+	}
+	if payload.Pid == 0 {
+		return 0, fmt.Errorf("container %q is not running", id)
+	}
+	return payload.Pid, nil
+}
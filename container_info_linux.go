@@ -0,0 +1,31 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build linux
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/google/gops/goprocess"
+)
+
+// printContainerDetails prints the container id, cgroup path, namespace
+// inode numbers, and effective capabilities of pid, when available.
+func printContainerDetails(pid int) {
+	if cgroup, err := goprocess.CgroupPath(pid); err == nil {
+		if id := goprocess.ContainerID(cgroup); id != "" {
+			fmt.Printf("container id:\t%v\n", id)
+		}
+		fmt.Printf("cgroup path:\t%v\n", cgroup)
+	}
+	if ns, err := goprocess.ReadNamespaces(pid); err == nil {
+		fmt.Printf("mnt ns:\t\t%v\n", ns.Mount)
+		fmt.Printf("pid ns:\t\t%v\n", ns.PID)
+	}
+	if caps, err := goprocess.EffectiveCapabilities(pid); err == nil {
+		fmt.Printf("capabilities:\t%#x\n", caps)
+	}
+}
@@ -0,0 +1,11 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build !linux
+
+package main
+
+// printContainerDetails is a no-op outside of Linux, where /proc-based
+// container inspection is unavailable.
+func printContainerDetails(pid int) {}
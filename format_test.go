@@ -0,0 +1,26 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import "testing"
+
+func TestFormatBytes(t *testing.T) {
+	tests := []struct {
+		bytes uint64
+		want  string
+	}{
+		{bytes: 0, want: "0B"},
+		{bytes: 512, want: "512B"},
+		{bytes: 1024, want: "1.0KiB"},
+		{bytes: 1536, want: "1.5KiB"},
+		{bytes: 1024 * 1024, want: "1.0MiB"},
+		{bytes: 1024 * 1024 * 1024, want: "1.0GiB"},
+	}
+	for _, tt := range tests {
+		if got := formatBytes(tt.bytes); got != tt.want {
+			t.Errorf("formatBytes(%d) = %q, want %q", tt.bytes, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,16 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goprocess
+
+// Find returns the P describing pid, if pid is currently a running Go
+// process known to gops.
+func Find(pid int) (p P, ok bool) {
+	for _, candidate := range FindAll() {
+		if candidate.PID == pid {
+			return candidate, true
+		}
+	}
+	return P{}, false
+}
@@ -0,0 +1,25 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goprocess
+
+// MockSource is a Source backed by a fixed slice of processes, letting
+// tests exercise the CLI's display and formatting code without touching
+// the real system.
+type MockSource struct {
+	Processes []P
+}
+
+// FindAll implements Source.
+func (m MockSource) FindAll() []P { return m.Processes }
+
+// Find implements Source.
+func (m MockSource) Find(pid int) (P, bool) {
+	for _, p := range m.Processes {
+		if p.PID == pid {
+			return p, true
+		}
+	}
+	return P{}, false
+}
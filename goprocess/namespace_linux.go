@@ -0,0 +1,231 @@
+// +build linux
+
+package goprocess
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// Namespaces holds the inode numbers of the Linux namespaces a process
+// belongs to, as reported under /proc/<pid>/ns.
+type Namespaces struct {
+	Mount int64
+	PID   int64
+	Net   int64
+	UTS   int64
+}
+
+// JoinNamespaces enters the mount and PID namespaces of pid via setns(2) so
+// that a subsequent /proc walk or socket dial observes the target's
+// container filesystem, mirroring the approach psgo uses for `podman top`.
+// setns is per-thread, so the caller must have called runtime.LockOSThread
+// before calling JoinNamespaces and must not unlock it until after restore
+// has been called.
+func JoinNamespaces(pid int) (restore func() error, err error) {
+	selfPaths := []string{"/proc/self/ns/mnt", "/proc/self/ns/pid"}
+	selfFDs := make([]int, len(selfPaths))
+	for i, p := range selfPaths {
+		fd, err := syscall.Open(p, syscall.O_RDONLY, 0)
+		if err != nil {
+			closeFDs(selfFDs[:i])
+			return nil, fmt.Errorf("goprocess: open %s: %w", p, err)
+		}
+		selfFDs[i] = fd
+	}
+
+	// joined tracks how many of targetPaths were actually entered, so a
+	// failure partway through (e.g. the target exits mid-call) only rolls
+	// back the namespaces this call actually joined rather than leaving
+	// the calling thread stuck half inside the target's namespaces.
+	targetPaths := []string{
+		fmt.Sprintf("/proc/%d/ns/mnt", pid),
+		fmt.Sprintf("/proc/%d/ns/pid", pid),
+	}
+	joined := 0
+	for _, p := range targetPaths {
+		fd, err := syscall.Open(p, syscall.O_RDONLY, 0)
+		if err != nil {
+			restoreNamespaces(selfPaths, selfFDs, joined)
+			closeFDs(selfFDs)
+			return nil, fmt.Errorf("goprocess: open %s: %w", p, err)
+		}
+		setErr := setns(fd, 0)
+		syscall.Close(fd)
+		if setErr != nil {
+			restoreNamespaces(selfPaths, selfFDs, joined)
+			closeFDs(selfFDs)
+			return nil, fmt.Errorf("goprocess: setns %s: %w", p, setErr)
+		}
+		joined++
+	}
+
+	return func() error {
+		defer closeFDs(selfFDs)
+		return restoreNamespaces(selfPaths, selfFDs, len(selfFDs))
+	}, nil
+}
+
+// restoreNamespaces calls setns back to the first count of selfFDs,
+// undoing a JoinNamespaces call (in full, or partially after a failed
+// join). It returns the first error encountered, if any, but still
+// attempts every restore so one failure doesn't strand the others.
+func restoreNamespaces(selfPaths []string, selfFDs []int, count int) error {
+	var firstErr error
+	for i := 0; i < count; i++ {
+		if err := setns(selfFDs[i], 0); err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("goprocess: restore %s: %w", selfPaths[i], err)
+		}
+	}
+	return firstErr
+}
+
+// closeFDs closes every fd in fds, ignoring errors; it is used for cleanup
+// on paths that are already returning a different error.
+func closeFDs(fds []int) {
+	for _, fd := range fds {
+		syscall.Close(fd)
+	}
+}
+
+// FindAllInNamespace joins the mount and PID namespaces of pid via
+// JoinNamespaces and enumerates the Go processes visible from there, i.e.
+// the processes running inside that container, reading /proc directly the
+// way ProcSource does. This is what lets a `gops` run on the host discover
+// Go processes inside a container rather than just resolving one PID.
+func FindAllInNamespace(pid int) ([]P, error) {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	restore, err := JoinNamespaces(pid)
+	if err != nil {
+		return nil, err
+	}
+	defer restore()
+
+	return ProcSource{}.FindAll(), nil
+}
+
+func setns(fd int, flags uintptr) error {
+	_, _, errno := syscall.Syscall(syscall.SYS_SETNS, uintptr(fd), flags, 0)
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+// ReadNamespaces parses the namespace inode numbers of pid out of the
+// symlinks under /proc/<pid>/ns, e.g. "mnt:[4026531840]".
+func ReadNamespaces(pid int) (Namespaces, error) {
+	var ns Namespaces
+	fields := map[string]*int64{
+		"mnt": &ns.Mount,
+		"pid": &ns.PID,
+		"net": &ns.Net,
+		"uts": &ns.UTS,
+	}
+	for name, dst := range fields {
+		link, err := os.Readlink(fmt.Sprintf("/proc/%d/ns/%s", pid, name))
+		if err != nil {
+			return ns, err
+		}
+		inode, err := parseNamespaceLink(link)
+		if err != nil {
+			return ns, err
+		}
+		*dst = inode
+	}
+	return ns, nil
+}
+
+func parseNamespaceLink(link string) (int64, error) {
+	start := strings.IndexByte(link, '[')
+	end := strings.IndexByte(link, ']')
+	if start < 0 || end < 0 || end < start {
+		return 0, fmt.Errorf("goprocess: malformed namespace link %q", link)
+	}
+	return strconv.ParseInt(link[start+1:end], 10, 64)
+}
+
+// CgroupPath returns the cgroup path of pid as reported by
+// /proc/<pid>/cgroup, preferring the unified cgroup v2 entry when present.
+func CgroupPath(pid int) (string, error) {
+	f, err := os.Open(fmt.Sprintf("/proc/%d/cgroup", pid))
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	var fallback string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		// Format: hierarchy-ID:controller-list:cgroup-path
+		fields := strings.SplitN(scanner.Text(), ":", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		if fields[0] == "0" && fields[1] == "" {
+			return fields[2], nil
+		}
+		fallback = fields[2]
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	return fallback, nil
+}
+
+// ContainerID extracts a container ID from a cgroup path such as
+// "/docker/<id>" or "/kubepods/.../<pod>/<id>.scope". It returns "" when
+// the path does not look like it belongs to a container.
+func ContainerID(cgroupPath string) string {
+	parts := strings.Split(strings.Trim(cgroupPath, "/"), "/")
+	if len(parts) == 0 {
+		return ""
+	}
+	last := strings.TrimSuffix(parts[len(parts)-1], ".scope")
+	if idx := strings.LastIndexByte(last, '-'); idx >= 0 {
+		last = last[idx+1:]
+	}
+	if len(last) < 12 {
+		return ""
+	}
+	for _, r := range last {
+		if !strings.ContainsRune("0123456789abcdef", r) {
+			return ""
+		}
+	}
+	return last
+}
+
+// EffectiveCapabilities reads the CapEff bitmask reported in
+// /proc/<pid>/status.
+func EffectiveCapabilities(pid int) (uint64, error) {
+	f, err := os.Open(fmt.Sprintf("/proc/%d/status", pid))
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "CapEff:") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return 0, fmt.Errorf("goprocess: malformed CapEff line %q", line)
+		}
+		return strconv.ParseUint(fields[1], 16, 64)
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, err
+	}
+	return 0, fmt.Errorf("goprocess: CapEff not found for pid %d", pid)
+}
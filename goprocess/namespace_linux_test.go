@@ -0,0 +1,67 @@
+// +build linux
+
+package goprocess
+
+import "testing"
+
+func TestParseNamespaceLink(t *testing.T) {
+	tests := []struct {
+		link    string
+		want    int64
+		wantErr bool
+	}{
+		{link: "mnt:[4026531840]", want: 4026531840},
+		{link: "pid:[4026531836]", want: 4026531836},
+		{link: "malformed", wantErr: true},
+		{link: "mnt:[]", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := parseNamespaceLink(tt.link)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("parseNamespaceLink(%q): expected error, got nil", tt.link)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseNamespaceLink(%q): unexpected error: %v", tt.link, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseNamespaceLink(%q) = %d, want %d", tt.link, got, tt.want)
+		}
+	}
+}
+
+func TestContainerID(t *testing.T) {
+	tests := []struct {
+		cgroup string
+		want   string
+	}{
+		{
+			cgroup: "/docker/e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+			want:   "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+		},
+		{
+			cgroup: "/kubepods/burstable/pod1234/e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855.scope",
+			want:   "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+		},
+		{
+			cgroup: "/system.slice/docker-e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855.scope",
+			want:   "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+		},
+		{
+			cgroup: "/user.slice/user-1000.slice",
+			want:   "",
+		},
+		{
+			cgroup: "/",
+			want:   "",
+		},
+	}
+	for _, tt := range tests {
+		if got := ContainerID(tt.cgroup); got != tt.want {
+			t.Errorf("ContainerID(%q) = %q, want %q", tt.cgroup, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+// +build !linux
+
+package goprocess
+
+import "fmt"
+
+// Namespaces holds the inode numbers of the Linux namespaces a process
+// belongs to. It is always zero outside of Linux.
+type Namespaces struct {
+	Mount int64
+	PID   int64
+	Net   int64
+	UTS   int64
+}
+
+var errUnsupported = fmt.Errorf("goprocess: namespace inspection is only supported on Linux")
+
+// JoinNamespaces is unsupported outside of Linux.
+func JoinNamespaces(pid int) (restore func() error, err error) {
+	return nil, errUnsupported
+}
+
+// FindAllInNamespace is unsupported outside of Linux.
+func FindAllInNamespace(pid int) ([]P, error) {
+	return nil, errUnsupported
+}
+
+// ReadNamespaces is unsupported outside of Linux.
+func ReadNamespaces(pid int) (Namespaces, error) {
+	return Namespaces{}, errUnsupported
+}
+
+// CgroupPath is unsupported outside of Linux.
+func CgroupPath(pid int) (string, error) {
+	return "", errUnsupported
+}
+
+// ContainerID extracts a container ID from a cgroup path. Outside of Linux
+// there is no cgroup path to parse, so it always returns "".
+func ContainerID(cgroupPath string) string {
+	return ""
+}
+
+// EffectiveCapabilities is unsupported outside of Linux.
+func EffectiveCapabilities(pid int) (uint64, error) {
+	return 0, errUnsupported
+}
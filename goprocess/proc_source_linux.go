@@ -0,0 +1,115 @@
+// +build linux
+
+package goprocess
+
+import (
+	"debug/buildinfo"
+	"debug/elf"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ProcSource is a Source that reads /proc directly instead of shelling out
+// or linking gopsutil, avoiding CGO and keeping gops's dependency footprint
+// small — the same approach tools like mitchellh/go-ps take, specialized
+// here for the "is this a Go binary" question via the ELF build ID note
+// the Go linker always emits.
+type ProcSource struct{}
+
+// FindAll implements Source.
+func (ProcSource) FindAll() []P {
+	entries, err := ioutil.ReadDir("/proc")
+	if err != nil {
+		return nil
+	}
+	var ps []P
+	for _, e := range entries {
+		pid, err := strconv.Atoi(e.Name())
+		if err != nil {
+			continue
+		}
+		if p, ok := readProcProcess(pid); ok {
+			ps = append(ps, p)
+		}
+	}
+	return ps
+}
+
+// Find implements Source.
+func (ProcSource) Find(pid int) (P, bool) {
+	return readProcProcess(pid)
+}
+
+func readProcProcess(pid int) (P, bool) {
+	exe, err := os.Readlink(fmt.Sprintf("/proc/%d/exe", pid))
+	if err != nil || !isGoBinary(exe) {
+		return P{}, false
+	}
+
+	ppid, _ := readPPID(pid)
+	cmdline, _ := ioutil.ReadFile(fmt.Sprintf("/proc/%d/cmdline", pid))
+
+	return P{
+		PID:          pid,
+		PPID:         ppid,
+		Exec:         filepath.Base(exe),
+		Path:         strings.ReplaceAll(strings.TrimRight(string(cmdline), "\x00"), "\x00", " "),
+		BuildVersion: readBuildVersion(exe),
+		Agent:        hasAgentFile(pid),
+	}, true
+}
+
+// readBuildVersion reads the Go version embedded in the binary at path by
+// the Go linker, returning "" if it cannot be determined (e.g. a stripped
+// binary).
+func readBuildVersion(path string) string {
+	info, err := buildinfo.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return info.GoVersion
+}
+
+// hasAgentFile reports whether pid has registered a gops agent, the same
+// way the gopsutil-backed source detects it: the agent writes a PID file
+// under os.TempDir()/gopsagent on startup.
+func hasAgentFile(pid int) bool {
+	_, err := os.Stat(filepath.Join(os.TempDir(), "gopsagent", strconv.Itoa(pid)))
+	return err == nil
+}
+
+// readPPID parses the PPID field out of /proc/<pid>/stat. The comm field
+// may itself contain spaces and parentheses, so fields are read starting
+// after the last ')' rather than by naive whitespace splitting.
+func readPPID(pid int) (int, error) {
+	data, err := ioutil.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return 0, err
+	}
+	line := string(data)
+	idx := strings.LastIndexByte(line, ')')
+	if idx < 0 {
+		return 0, fmt.Errorf("goprocess: malformed /proc/%d/stat", pid)
+	}
+	fields := strings.Fields(line[idx+1:])
+	if len(fields) < 2 {
+		return 0, fmt.Errorf("goprocess: malformed /proc/%d/stat", pid)
+	}
+	return strconv.Atoi(fields[1])
+}
+
+// isGoBinary reports whether the ELF binary at path was built by the Go
+// toolchain, by checking for the .note.go.buildid section the Go linker
+// always emits.
+func isGoBinary(path string) bool {
+	f, err := elf.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	return f.Section(".note.go.buildid") != nil
+}
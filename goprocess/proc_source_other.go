@@ -0,0 +1,13 @@
+// +build !linux
+
+package goprocess
+
+// ProcSource is a pure-/proc Source and is only available on Linux; on
+// other platforms it returns no processes.
+type ProcSource struct{}
+
+// FindAll implements Source.
+func (ProcSource) FindAll() []P { return nil }
+
+// Find implements Source.
+func (ProcSource) Find(pid int) (P, bool) { return P{}, false }
@@ -0,0 +1,23 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goprocess
+
+// Source discovers Go processes running on the system. The CLI depends on
+// this interface rather than calling FindAll/Find directly so that its
+// process source can be swapped via `--source`.
+type Source interface {
+	FindAll() []P
+	Find(pid int) (P, bool)
+}
+
+// GopsutilSource is the default Source, backed by gopsutil/process. It
+// delegates to the package-level FindAll and Find functions.
+type GopsutilSource struct{}
+
+// FindAll implements Source.
+func (GopsutilSource) FindAll() []P { return FindAll() }
+
+// Find implements Source.
+func (GopsutilSource) Find(pid int) (P, bool) { return Find(pid) }
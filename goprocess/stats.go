@@ -0,0 +1,97 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package goprocess
+
+import (
+	"fmt"
+
+	"github.com/shirou/gopsutil/process"
+)
+
+// Stats holds resource usage figures for a single process (Self) alongside
+// the sum of those figures across its entire descendant subtree
+// (Cumulative), keyed by the process' own PID.
+type Stats struct {
+	Self       Usage
+	Cumulative Usage
+	Children   map[int]Stats
+}
+
+// Usage is a snapshot of the resource figures gops tracks per process.
+type Usage struct {
+	RSSBytes uint64
+	CPU      float64
+	Threads  int32
+	OpenFDs  int32
+}
+
+// SubtreeStats recursively walks pid's descendants, as reported by ps, and
+// sums their resource usage. It is useful for supervisors that fork many Go
+// workers, where the parent's own RSS/CPU figures alone are misleading.
+func SubtreeStats(pid int, ps []P) (Stats, error) {
+	tree := make(map[int][]P)
+	for _, p := range ps {
+		tree[p.PPID] = append(tree[p.PPID], p)
+	}
+	return subtreeStats(pid, tree, map[int]bool{})
+}
+
+// subtreeStats recurses over tree, tracking seen PIDs the same way
+// constructProcessTree does so that a malformed or adversarial snapshot
+// (e.g. two processes reporting each other as parent) can't send it into
+// infinite recursion.
+func subtreeStats(pid int, tree map[int][]P, seen map[int]bool) (Stats, error) {
+	if seen[pid] {
+		return Stats{}, fmt.Errorf("goprocess: cycle detected at pid %d", pid)
+	}
+	seen[pid] = true
+
+	self, err := usage(pid)
+	if err != nil {
+		return Stats{}, err
+	}
+
+	stats := Stats{
+		Self:       self,
+		Cumulative: self,
+		Children:   make(map[int]Stats),
+	}
+	for _, child := range tree[pid] {
+		childStats, err := subtreeStats(child.PID, tree, seen)
+		if err != nil {
+			// A child may have exited between the ps snapshot and this
+			// walk, or formed a cycle; skip it rather than failing the
+			// whole subtree.
+			continue
+		}
+		stats.Children[child.PID] = childStats
+		stats.Cumulative.RSSBytes += childStats.Cumulative.RSSBytes
+		stats.Cumulative.CPU += childStats.Cumulative.CPU
+		stats.Cumulative.Threads += childStats.Cumulative.Threads
+		stats.Cumulative.OpenFDs += childStats.Cumulative.OpenFDs
+	}
+	return stats, nil
+}
+
+func usage(pid int) (Usage, error) {
+	p, err := process.NewProcess(int32(pid))
+	if err != nil {
+		return Usage{}, err
+	}
+	var u Usage
+	if v, err := p.MemoryInfo(); err == nil {
+		u.RSSBytes = v.RSS
+	}
+	if v, err := p.CPUPercent(); err == nil {
+		u.CPU = v
+	}
+	if v, err := p.NumThreads(); err == nil {
+		u.Threads = v
+	}
+	if fds, err := p.OpenFiles(); err == nil {
+		u.OpenFDs = int32(len(fds))
+	}
+	return u, nil
+}
@@ -0,0 +1,23 @@
+package goprocess
+
+import (
+	"os"
+	"testing"
+)
+
+// TestSubtreeStatsCycleGuard ensures a process that (erroneously) reports
+// itself as its own child is skipped rather than recursed into forever.
+func TestSubtreeStatsCycleGuard(t *testing.T) {
+	pid := os.Getpid()
+	tree := map[int][]P{
+		pid: {{PID: pid, PPID: pid}},
+	}
+
+	stats, err := subtreeStats(pid, tree, map[int]bool{})
+	if err != nil {
+		t.Fatalf("subtreeStats: %v", err)
+	}
+	if len(stats.Children) != 0 {
+		t.Fatalf("expected the cyclic child to be skipped, got %d children", len(stats.Children))
+	}
+}
@@ -33,6 +33,26 @@ Commands:
     stats       	Prints the vital runtime stats.
     help        	Prints this help text.
 
+Flags:
+    --source src	Selects the goprocess.Source used to discover processes:
+                	gopsutil (default), proc (Linux, no CGO), or mock.
+                	Must be the first argument, e.g. "gops --source proc ps".
+    --container 	Resolves a Docker/containerd/CRI-O container id or name
+                	to its init PID, prints its container details, and
+                	lists the Go processes running inside the container.
+    tree --stats	Annotates the process tree with per-node and cumulative
+                	subtree RSS, CPU%, thread count, and open FD count.
+    ps -o cols  	Lists Go processes with user-selected columns, e.g.
+                	"gops ps -o pid,ppid,user,rss,pcpu --sort rss --format json".
+                	Supports --sort <col>, --filter <col>=~<regex>, and
+                	--format {table,json,csv,tsv}.
+    watch       	Live top-like view of Go processes, refreshed on an
+                	interval. Supports --interval <duration> and
+                	--sort {rss,cpu,pid}.
+    serve       	Serves an HTTP+JSON API (/processes, /tree, /metrics, ...)
+                	so remote clients can pull the same data without SSH.
+                	Supports --listen <addr>.
+
 Profiling commands:
     trace       	Runs the runtime tracer for 5 secs and launches "go tool trace".
     pprof-heap  	Reads the heap profile and launches "go tool pprof".
@@ -44,7 +64,21 @@ Symbol "*" indicates the process runs the agent.`
 
 // TODO(jbd): add link that explains the use of agent.
 
+// procSource is the goprocess.Source used to discover Go processes.
+// Selectable via the leading `--source` flag; defaults to the
+// gopsutil-backed source.
+var procSource goprocess.Source = goprocess.GopsutilSource{}
+
 func main() {
+	if len(os.Args) >= 3 && os.Args[1] == "--source" {
+		src, err := parseSource(os.Args[2])
+		if err != nil {
+			usage(err.Error())
+		}
+		procSource = src
+		os.Args = append(os.Args[:1], os.Args[3:]...)
+	}
+
 	if len(os.Args) < 2 {
 		processes()
 		return
@@ -52,6 +86,25 @@ func main() {
 
 	cmd := os.Args[1]
 
+	if cmd == "--container" {
+		if len(os.Args) < 3 {
+			usage("--container requires a container id or name")
+		}
+		pid, err := resolveContainerPID(os.Args[2])
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		printContainerDetails(pid)
+		ps, err := goprocess.FindAllInNamespace(pid)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		printProcesses(ps)
+		return
+	}
+
 	// See if it is a PID.
 	pid, err := strconv.Atoi(cmd)
 	if err == nil {
@@ -64,7 +117,32 @@ func main() {
 	}
 
 	if cmd == "tree" {
-		displayProcessTree()
+		withStats := len(os.Args) > 2 && os.Args[2] == "--stats"
+		displayProcessTree(withStats)
+		return
+	}
+
+	if cmd == "ps" {
+		if err := runPS(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if cmd == "watch" {
+		if err := runWatch(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if cmd == "serve" {
+		if err := runServe(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
 		return
 	}
 
@@ -87,9 +165,27 @@ func main() {
 	}
 }
 
+// parseSource resolves a --source flag value to a goprocess.Source.
+func parseSource(name string) (goprocess.Source, error) {
+	switch name {
+	case "gopsutil":
+		return goprocess.GopsutilSource{}, nil
+	case "proc":
+		return goprocess.ProcSource{}, nil
+	case "mock":
+		return goprocess.MockSource{}, nil
+	default:
+		return nil, fmt.Errorf("unknown --source %q, want one of gopsutil, proc, mock", name)
+	}
+}
+
 func processes() {
-	ps := goprocess.FindAll()
+	printProcesses(procSource.FindAll())
+}
 
+// printProcesses prints ps in the fixed-width columns `gops` has always
+// used for its default, no-args listing.
+func printProcesses(ps []goprocess.P) {
 	var maxPID, maxPPID, maxExec, maxVersion int
 	for i, p := range ps {
 		ps[i].BuildVersion = shortenVersion(p.BuildVersion)
@@ -154,35 +250,51 @@ func processInfo(pid int) {
 			}
 		}
 	}
+	printContainerDetails(pid)
 }
 
 // pstree contains a mapping between the PPIDs and the child processes.
 var pstree map[int][]goprocess.P
 
-// displayProcessTree displays a tree of all the running Go processes.
-func displayProcessTree() {
-	ps := goprocess.FindAll()
+// displayProcessTree displays a tree of all the running Go processes. When
+// withStats is true, each node is annotated with its own and its subtree's
+// cumulative RSS, CPU%, thread count, and open FD count.
+func displayProcessTree(withStats bool) {
+	ps := procSource.FindAll()
 	pstree = make(map[int][]goprocess.P)
 	for _, p := range ps {
 		pstree[p.PPID] = append(pstree[p.PPID], p)
 	}
+	subtree := map[int]goprocess.Stats{}
+	if withStats {
+		for _, p := range ps {
+			if s, err := goprocess.SubtreeStats(p.PID, ps); err == nil {
+				subtree[p.PID] = s
+			}
+		}
+	}
 	tree := treeprint.New()
 	tree.SetValue("...")
 	seen := map[int]bool{}
 	for _, p := range ps {
-		constructProcessTree(p.PPID, p, seen, tree)
+		constructProcessTree(p.PPID, p, seen, tree, subtree)
 	}
 	fmt.Println(tree.String())
 }
 
 // constructProcessTree constructs the process tree in a depth-first fashion.
-func constructProcessTree(ppid int, process goprocess.P, seen map[int]bool, tree treeprint.Tree) {
+// subtree, when non-nil, maps a PID to its subtree resource stats and is
+// used to annotate each node.
+func constructProcessTree(ppid int, process goprocess.P, seen map[int]bool, tree treeprint.Tree, subtree map[int]goprocess.Stats) {
 	if seen[ppid] {
 		return
 	}
 	seen[ppid] = true
 	if ppid != process.PPID {
 		output := strconv.Itoa(ppid) + " (" + process.Exec + ")" + " {" + process.BuildVersion + "}"
+		if s, ok := subtree[process.PID]; ok {
+			output += " " + formatStats(s)
+		}
 		if process.Agent {
 			tree = tree.AddMetaBranch("*", output)
 		} else {
@@ -193,8 +305,33 @@ func constructProcessTree(ppid int, process goprocess.P, seen map[int]bool, tree
 	}
 	for index := range pstree[ppid] {
 		process := pstree[ppid][index]
-		constructProcessTree(process.PID, process, seen, tree)
+		constructProcessTree(process.PID, process, seen, tree, subtree)
+	}
+}
+
+// formatStats renders a Stats value as "[self: <usage> | subtree: <usage>]",
+// each usage formatted by formatUsage, for display alongside a tree node.
+func formatStats(s goprocess.Stats) string {
+	return fmt.Sprintf("[self: %s | subtree: %s]", formatUsage(s.Self), formatUsage(s.Cumulative))
+}
+
+// formatUsage renders a single Usage value as "rss=.. cpu=..% threads=.. fds=..".
+func formatUsage(u goprocess.Usage) string {
+	return fmt.Sprintf("rss=%s cpu=%.1f%% threads=%d fds=%d", formatBytes(u.RSSBytes), u.CPU, u.Threads, u.OpenFDs)
+}
+
+// formatBytes renders a byte count using the same units `top` and `ps` use.
+func formatBytes(b uint64) string {
+	const unit = 1024
+	if b < unit {
+		return fmt.Sprintf("%dB", b)
+	}
+	div, exp := uint64(unit), 0
+	for n := b / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
 	}
+	return fmt.Sprintf("%.1f%ciB", float64(b)/float64(div), "KMGTPE"[exp])
 }
 
 var develRe = regexp.MustCompile(`devel\s+\+\w+`)
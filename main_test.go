@@ -0,0 +1,71 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/google/gops/goprocess"
+)
+
+// TestDisplayProcessTreeWithMockSource exercises the tree renderer end to
+// end using goprocess.MockSource, the deterministic source the mock exists
+// to enable.
+func TestDisplayProcessTreeWithMockSource(t *testing.T) {
+	orig := procSource
+	defer func() { procSource = orig }()
+
+	procSource = goprocess.MockSource{Processes: []goprocess.P{
+		{PID: 1, PPID: 0, Exec: "init"},
+		{PID: 2, PPID: 1, Exec: "myapp", BuildVersion: "go1.21", Agent: true},
+		{PID: 3, PPID: 2, Exec: "worker", BuildVersion: "go1.21"},
+	}}
+
+	out := captureStdout(t, func() { displayProcessTree(false) })
+
+	for _, want := range []string{"myapp", "worker", "{go1.21}"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("displayProcessTree output missing %q, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestPrintProcessesWithMockSource(t *testing.T) {
+	orig := procSource
+	defer func() { procSource = orig }()
+
+	procSource = goprocess.MockSource{Processes: []goprocess.P{
+		{PID: 10, PPID: 1, Exec: "myapp", BuildVersion: "go1.21", Path: "/usr/bin/myapp"},
+	}}
+	out := captureStdout(t, processes)
+	if !strings.Contains(out, "myapp") || !strings.Contains(out, "/usr/bin/myapp") {
+		t.Errorf("printProcesses output missing expected fields, got:\n%s", out)
+	}
+}
+
+// captureStdout redirects os.Stdout for the duration of fn and returns
+// everything written to it.
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe: %v", err)
+	}
+	real := os.Stdout
+	os.Stdout = w
+	fn()
+	w.Close()
+	os.Stdout = real
+
+	var buf bytes.Buffer
+	if _, err := io.Copy(&buf, r); err != nil {
+		t.Fatalf("io.Copy: %v", err)
+	}
+	return buf.String()
+}
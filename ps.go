@@ -0,0 +1,332 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/google/gops/goprocess"
+	"github.com/shirou/gopsutil/process"
+)
+
+// Column describes one column of `gops ps -o` output: how it is labeled,
+// how its value is formatted for display, and how it is compared for
+// `--sort`. SortValue is nil for columns with no natural numeric ordering
+// (e.g. exec, cmdline), in which case --sort falls back to the formatted
+// string.
+type Column struct {
+	Name      string
+	Header    string
+	Extract   func(goprocess.P) string
+	SortValue func(goprocess.P) float64
+}
+
+// columns is the registry of columns available to `gops ps -o`.
+var columns = map[string]Column{
+	"pid": {Name: "pid", Header: "PID",
+		Extract:   func(p goprocess.P) string { return strconv.Itoa(p.PID) },
+		SortValue: func(p goprocess.P) float64 { return float64(p.PID) }},
+	"ppid": {Name: "ppid", Header: "PPID",
+		Extract:   func(p goprocess.P) string { return strconv.Itoa(p.PPID) },
+		SortValue: func(p goprocess.P) float64 { return float64(p.PPID) }},
+	"exec":    {Name: "exec", Header: "EXEC", Extract: func(p goprocess.P) string { return p.Exec }},
+	"version": {Name: "version", Header: "VERSION", Extract: func(p goprocess.P) string { return p.BuildVersion }},
+	"cmdline": {Name: "cmdline", Header: "CMDLINE", Extract: func(p goprocess.P) string { return p.Path }},
+	"agent":   {Name: "agent", Header: "AGENT", Extract: func(p goprocess.P) string { return strconv.FormatBool(p.Agent) }},
+	"user":    {Name: "user", Header: "USER", Extract: extractFromProc(func(pp *process.Process) (string, error) { return pp.Username() })},
+	"rss": {Name: "rss", Header: "RSS",
+		Extract: func(p goprocess.P) string {
+			v, err := processRSS(p.PID)
+			if err != nil {
+				return ""
+			}
+			return formatBytes(v)
+		},
+		SortValue: func(p goprocess.P) float64 {
+			v, _ := processRSS(p.PID)
+			return float64(v)
+		}},
+	"pcpu": {Name: "pcpu", Header: "%CPU",
+		Extract: func(p goprocess.P) string {
+			v, err := processCPU(p.PID)
+			if err != nil {
+				return ""
+			}
+			return fmt.Sprintf("%.1f", v)
+		},
+		SortValue: func(p goprocess.P) float64 {
+			v, _ := processCPU(p.PID)
+			return v
+		}},
+	"threads": {Name: "threads", Header: "THREADS",
+		Extract: func(p goprocess.P) string {
+			v, err := processThreads(p.PID)
+			if err != nil {
+				return ""
+			}
+			return strconv.Itoa(int(v))
+		},
+		SortValue: func(p goprocess.P) float64 {
+			v, _ := processThreads(p.PID)
+			return float64(v)
+		}},
+	"nspid": {Name: "nspid", Header: "NSPID",
+		Extract: func(p goprocess.P) string {
+			ns, err := goprocess.ReadNamespaces(p.PID)
+			if err != nil {
+				return ""
+			}
+			return strconv.FormatInt(ns.PID, 10)
+		},
+		SortValue: func(p goprocess.P) float64 {
+			ns, _ := goprocess.ReadNamespaces(p.PID)
+			return float64(ns.PID)
+		}},
+	"cgroup": {Name: "cgroup", Header: "CGROUP", Extract: func(p goprocess.P) string {
+		cgroup, err := goprocess.CgroupPath(p.PID)
+		if err != nil {
+			return ""
+		}
+		return cgroup
+	}},
+	"caps": {Name: "caps", Header: "CAPS",
+		Extract: func(p goprocess.P) string {
+			caps, err := goprocess.EffectiveCapabilities(p.PID)
+			if err != nil {
+				return ""
+			}
+			return fmt.Sprintf("%#x", caps)
+		},
+		SortValue: func(p goprocess.P) float64 {
+			caps, _ := goprocess.EffectiveCapabilities(p.PID)
+			return float64(caps)
+		}},
+}
+
+// defaultColumns is used by `gops ps` when -o is not given.
+var defaultColumns = []string{"pid", "ppid", "exec", "agent", "version", "cmdline"}
+
+// extractFromProc adapts a gopsutil-based accessor into a Column.Extract
+// function, returning "" when the process can no longer be inspected.
+func extractFromProc(get func(*process.Process) (string, error)) func(goprocess.P) string {
+	return func(p goprocess.P) string {
+		pp, err := process.NewProcess(int32(p.PID))
+		if err != nil {
+			return ""
+		}
+		v, err := get(pp)
+		if err != nil {
+			return ""
+		}
+		return v
+	}
+}
+
+func processRSS(pid int) (uint64, error) {
+	pp, err := process.NewProcess(int32(pid))
+	if err != nil {
+		return 0, err
+	}
+	v, err := pp.MemoryInfo()
+	if err != nil {
+		return 0, err
+	}
+	return v.RSS, nil
+}
+
+func processCPU(pid int) (float64, error) {
+	pp, err := process.NewProcess(int32(pid))
+	if err != nil {
+		return 0, err
+	}
+	return pp.CPUPercent()
+}
+
+func processThreads(pid int) (int32, error) {
+	pp, err := process.NewProcess(int32(pid))
+	if err != nil {
+		return 0, err
+	}
+	return pp.NumThreads()
+}
+
+// psOptions holds the parsed flags of `gops ps`.
+type psOptions struct {
+	columns []string
+	sortBy  string
+	filter  string // "key=~regex"
+	format  string // table, json, csv, tsv
+}
+
+// parsePSArgs parses the arguments following `gops ps`.
+func parsePSArgs(args []string) (psOptions, error) {
+	opts := psOptions{columns: defaultColumns, format: "table"}
+	for i := 0; i < len(args); i++ {
+		switch {
+		case args[i] == "-o" && i+1 < len(args):
+			opts.columns = strings.Split(args[i+1], ",")
+			i++
+		case args[i] == "--sort" && i+1 < len(args):
+			opts.sortBy = args[i+1]
+			i++
+		case args[i] == "--filter" && i+1 < len(args):
+			opts.filter = args[i+1]
+			i++
+		case args[i] == "--format" && i+1 < len(args):
+			opts.format = args[i+1]
+			i++
+		default:
+			return opts, fmt.Errorf("gops ps: unrecognized argument %q", args[i])
+		}
+	}
+	for _, name := range opts.columns {
+		if _, ok := columns[name]; !ok {
+			return opts, fmt.Errorf("gops ps: unknown column %q", name)
+		}
+	}
+	return opts, nil
+}
+
+// runPS implements `gops ps`: it lists Go processes as a table of
+// user-selected columns, comparable to `ps -o`.
+func runPS(args []string) error {
+	opts, err := parsePSArgs(args)
+	if err != nil {
+		return err
+	}
+
+	ps := procSource.FindAll()
+
+	if opts.filter != "" {
+		ps, err = filterProcesses(ps, opts.filter)
+		if err != nil {
+			return err
+		}
+	}
+
+	if opts.sortBy != "" {
+		col, ok := columns[opts.sortBy]
+		if !ok {
+			return fmt.Errorf("gops ps: unknown sort column %q", opts.sortBy)
+		}
+		if col.SortValue != nil {
+			sort.SliceStable(ps, func(i, j int) bool { return col.SortValue(ps[i]) < col.SortValue(ps[j]) })
+		} else {
+			sort.SliceStable(ps, func(i, j int) bool { return col.Extract(ps[i]) < col.Extract(ps[j]) })
+		}
+	}
+
+	rows := make([][]string, len(ps))
+	headers := make([]string, len(opts.columns))
+	for i, name := range opts.columns {
+		headers[i] = columns[name].Header
+	}
+	for i, p := range ps {
+		row := make([]string, len(opts.columns))
+		for j, name := range opts.columns {
+			row[j] = columns[name].Extract(p)
+		}
+		rows[i] = row
+	}
+
+	switch opts.format {
+	case "table":
+		printTable(headers, rows)
+	case "json":
+		return printJSON(opts.columns, rows)
+	case "csv":
+		return printDelimited(headers, rows, ',')
+	case "tsv":
+		return printDelimited(headers, rows, '\t')
+	default:
+		return fmt.Errorf("gops ps: unknown format %q", opts.format)
+	}
+	return nil
+}
+
+// filterProcesses applies a "key=~regex" filter expression to ps.
+func filterProcesses(ps []goprocess.P, filter string) ([]goprocess.P, error) {
+	parts := strings.SplitN(filter, "=~", 2)
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("gops ps: --filter must look like key=~regex, got %q", filter)
+	}
+	col, ok := columns[parts[0]]
+	if !ok {
+		return nil, fmt.Errorf("gops ps: unknown filter column %q", parts[0])
+	}
+	re, err := regexp.Compile(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("gops ps: invalid filter regex: %w", err)
+	}
+	var out []goprocess.P
+	for _, p := range ps {
+		if re.MatchString(col.Extract(p)) {
+			out = append(out, p)
+		}
+	}
+	return out, nil
+}
+
+func printTable(headers []string, rows [][]string) {
+	widths := make([]int, len(headers))
+	for i, h := range headers {
+		widths[i] = len(h)
+	}
+	for _, row := range rows {
+		for i, cell := range row {
+			widths[i] = max(widths[i], len(cell))
+		}
+	}
+	printRow(headers, widths)
+	for _, row := range rows {
+		printRow(row, widths)
+	}
+}
+
+func printRow(cells []string, widths []int) {
+	for i, cell := range cells {
+		fmt.Print(pad(cell, widths[i]))
+		if i < len(cells)-1 {
+			fmt.Print(" ")
+		}
+	}
+	fmt.Println()
+}
+
+func printJSON(cols []string, rows [][]string) error {
+	out := make([]map[string]string, len(rows))
+	for i, row := range rows {
+		m := make(map[string]string, len(cols))
+		for j, name := range cols {
+			m[name] = row[j]
+		}
+		out[i] = m
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+func printDelimited(headers []string, rows [][]string, comma rune) error {
+	w := csv.NewWriter(os.Stdout)
+	w.Comma = comma
+	if err := w.Write(headers); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		if err := w.Write(row); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
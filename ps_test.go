@@ -0,0 +1,84 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/google/gops/goprocess"
+)
+
+func TestParsePSArgs(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want psOptions
+	}{
+		{
+			name: "defaults",
+			args: nil,
+			want: psOptions{columns: defaultColumns, format: "table"},
+		},
+		{
+			name: "all flags",
+			args: []string{"-o", "pid,rss", "--sort", "rss", "--filter", "exec=~myapp", "--format", "json"},
+			want: psOptions{columns: []string{"pid", "rss"}, sortBy: "rss", filter: "exec=~myapp", format: "json"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parsePSArgs(tt.args)
+			if err != nil {
+				t.Fatalf("parsePSArgs(%v): unexpected error: %v", tt.args, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parsePSArgs(%v) = %+v, want %+v", tt.args, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParsePSArgsUnknownColumn(t *testing.T) {
+	if _, err := parsePSArgs([]string{"-o", "bogus"}); err == nil {
+		t.Fatal("expected error for unknown column, got nil")
+	}
+}
+
+func TestFilterProcesses(t *testing.T) {
+	ps := []goprocess.P{
+		{PID: 1, Exec: "myapp"},
+		{PID: 2, Exec: "sidecar"},
+		{PID: 3, Exec: "myapp-worker"},
+	}
+	out, err := filterProcesses(ps, "exec=~^myapp")
+	if err != nil {
+		t.Fatalf("filterProcesses: %v", err)
+	}
+	if len(out) != 2 || out[0].PID != 1 || out[1].PID != 3 {
+		t.Errorf("filterProcesses returned %+v, want pids [1 3]", out)
+	}
+}
+
+func TestFilterProcessesInvalidExpression(t *testing.T) {
+	if _, err := filterProcesses(nil, "nosep"); err == nil {
+		t.Fatal("expected error for filter without =~, got nil")
+	}
+}
+
+// TestPIDColumnSortsNumerically guards against sorting by the formatted
+// display string, which would order "10" before "2".
+func TestPIDColumnSortsNumerically(t *testing.T) {
+	pids := []int{10, 2, 9}
+	for i := 0; i < len(pids); i++ {
+		for j := i + 1; j < len(pids); j++ {
+			iVal := columns["pid"].SortValue(goprocess.P{PID: pids[i]})
+			jVal := columns["pid"].SortValue(goprocess.P{PID: pids[j]})
+			if pids[i] < pids[j] && !(iVal < jVal) {
+				t.Fatalf("pid SortValue(%d)=%v should be less than SortValue(%d)=%v", pids[i], iVal, pids[j], jVal)
+			}
+		}
+	}
+}
@@ -0,0 +1,203 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/google/gops/goprocess"
+)
+
+// runServe implements `gops serve`: it starts an HTTP+JSON server that
+// exposes the same data the CLI prints today, so a remote client or a
+// Prometheus scraper can pull it without SSH-ing to the box.
+func runServe(args []string) error {
+	listen := ":7777"
+	for i := 0; i < len(args); i++ {
+		if args[i] == "--listen" && i+1 < len(args) {
+			listen = args[i+1]
+			i++
+		}
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/processes", handleProcesses)
+	mux.HandleFunc("/processes/", handleProcessesSubpath)
+	mux.HandleFunc("/tree", handleTree)
+	mux.HandleFunc("/metrics", handleMetrics)
+
+	fmt.Printf("gops: serving on %s\n", listen)
+	return http.ListenAndServe(listen, mux)
+}
+
+func handleProcesses(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, procSource.FindAll())
+}
+
+// handleProcessesSubpath dispatches /processes/{pid}[/stack|/memstats|/pprof/{heap,cpu}].
+func handleProcessesSubpath(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/processes/"), "/")
+	pid, err := strconv.Atoi(parts[0])
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid pid %q", parts[0]), http.StatusBadRequest)
+		return
+	}
+
+	if len(parts) == 1 {
+		p, ok := procSource.Find(pid)
+		if !ok {
+			http.Error(w, fmt.Sprintf("no Go process with pid %d", pid), http.StatusNotFound)
+			return
+		}
+		writeJSON(w, p)
+		return
+	}
+
+	var cmdName string
+	switch {
+	case len(parts) == 2 && parts[1] == "stack":
+		cmdName = "stack"
+	case len(parts) == 2 && parts[1] == "memstats":
+		cmdName = "memstats"
+	case len(parts) == 3 && parts[1] == "pprof" && parts[2] == "heap":
+		cmdName = "pprof-heap"
+	case len(parts) == 3 && parts[1] == "pprof" && parts[2] == "cpu":
+		cmdName = "pprof-cpu"
+	default:
+		http.NotFound(w, r)
+		return
+	}
+	streamAgentCommand(w, pid, cmdName)
+}
+
+// streamAgentCommand resolves pid to a TCP address the same way the CLI
+// does, then streams the named `cmds` table entry's stdout output straight
+// to the response body — this is what makes
+// "go tool pprof http://host:7777/processes/1234/pprof/heap" work.
+func streamAgentCommand(w http.ResponseWriter, pid int, name string) {
+	addr, err := targetToAddr(strconv.Itoa(pid))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	fn, ok := cmds[name]
+	if !ok {
+		http.Error(w, fmt.Sprintf("unknown agent command %q", name), http.StatusInternalServerError)
+		return
+	}
+	if err := streamCommand(w, func() error { return fn(*addr, nil) }); err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+	}
+}
+
+func handleTree(w http.ResponseWriter, r *http.Request) {
+	ps := procSource.FindAll()
+	tree := make(map[int][]goprocess.P)
+	for _, p := range ps {
+		tree[p.PPID] = append(tree[p.PPID], p)
+	}
+	writeJSON(w, tree)
+}
+
+// stdoutRedirectMu serializes access to the process-wide os.Stdout
+// redirection streamCommand performs. The existing agent commands print
+// directly to os.Stdout rather than returning their payload, and os.Stdout
+// is process-global state shared by every net/http handler goroutine, so
+// concurrent requests (e.g. a Prometheus scraper hitting /metrics while a
+// client streams a pprof-cpu capture) must not redirect it at the same
+// time or their response bodies get corrupted/cross-wired.
+var stdoutRedirectMu sync.Mutex
+
+// streamCommand runs cmd with os.Stdout redirected to a pipe and copies
+// everything it writes to w. Only one streamCommand call runs at a time
+// process-wide; a slow capture (e.g. pprof-cpu, which can take tens of
+// seconds) delays other captures rather than racing with them.
+func streamCommand(w io.Writer, cmd func() error) error {
+	stdoutRedirectMu.Lock()
+	defer stdoutRedirectMu.Unlock()
+
+	r, pw, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+
+	realStdout := os.Stdout
+	os.Stdout = pw
+	done := make(chan error, 1)
+	go func() {
+		done <- cmd()
+		pw.Close()
+	}()
+
+	_, copyErr := io.Copy(w, r)
+	// If the client disconnected mid-capture, io.Copy returns as soon as
+	// writing to w fails, while cmd() may still be writing to pw. Close r
+	// so those writes fail with a broken-pipe error instead of blocking
+	// forever, then wait for cmd() to actually finish before restoring
+	// os.Stdout — restoring it any earlier would redirect cmd()'s
+	// still-in-flight writes to the real stdout instead of discarding them.
+	r.Close()
+	cmdErr := <-done
+	os.Stdout = realStdout
+
+	if cmdErr != nil {
+		return cmdErr
+	}
+	return copyErr
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+var (
+	heapAllocRe = regexp.MustCompile(`(?i)heap[_ ]?alloc[^0-9]*([0-9]+)`)
+	gcPauseRe   = regexp.MustCompile(`(?i)gc[_ ]?pause[^0-9]*([0-9]+)`)
+	goroutineRe = regexp.MustCompile(`(?i)goroutines?[^0-9]*([0-9]+)`)
+)
+
+// handleMetrics emits Prometheus text-format gauges for every reachable Go
+// process, sourced from the agent's memstats/stats output.
+func handleMetrics(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	for _, p := range procSource.FindAll() {
+		if !p.Agent {
+			continue
+		}
+		addr, err := targetToAddr(strconv.Itoa(p.PID))
+		if err != nil {
+			continue
+		}
+		var buf strings.Builder
+		if err := streamCommand(&buf, func() error { return cmds["memstats"](*addr, nil) }); err != nil {
+			continue
+		}
+		if err := streamCommand(&buf, func() error { return cmds["stats"](*addr, nil) }); err != nil {
+			continue
+		}
+		writeGauge(w, "heap_alloc", p.PID, heapAllocRe, buf.String())
+		writeGauge(w, "gc_pause", p.PID, gcPauseRe, buf.String())
+		writeGauge(w, "goroutines", p.PID, goroutineRe, buf.String())
+	}
+}
+
+func writeGauge(w io.Writer, name string, pid int, re *regexp.Regexp, output string) {
+	m := re.FindStringSubmatch(output)
+	if m == nil {
+		return
+	}
+	fmt.Fprintf(w, "gops_%s{pid=\"%d\"} %s\n", name, pid, m[1])
+}
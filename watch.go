@@ -0,0 +1,209 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/google/gops/goprocess"
+	"github.com/shirou/gopsutil/process"
+)
+
+const (
+	ansiClear  = "\033[H\033[2J"
+	ansiGreen  = "\033[32m"
+	ansiRed    = "\033[31m"
+	ansiYellow = "\033[33m"
+	ansiReset  = "\033[0m"
+)
+
+// rssChangeThreshold and cpuChangeThreshold are the deltas past which a
+// process is highlighted as having changed between watch ticks.
+const (
+	rssChangeThreshold = 5 * 1024 * 1024 // 5 MiB
+	cpuChangeThreshold = 5.0             // percentage points
+)
+
+// watchOptions holds the parsed flags of `gops watch`.
+type watchOptions struct {
+	interval time.Duration
+	sortBy   string // rss, cpu, pid
+}
+
+// parseWatchArgs parses the arguments following `gops watch`.
+func parseWatchArgs(args []string) (watchOptions, error) {
+	opts := watchOptions{interval: 2 * time.Second, sortBy: "pid"}
+	for i := 0; i < len(args); i++ {
+		switch {
+		case args[i] == "--interval" && i+1 < len(args):
+			d, err := time.ParseDuration(args[i+1])
+			if err != nil {
+				return opts, fmt.Errorf("gops watch: invalid --interval: %w", err)
+			}
+			opts.interval = d
+			i++
+		case args[i] == "--sort" && i+1 < len(args):
+			opts.sortBy = args[i+1]
+			i++
+		default:
+			return opts, fmt.Errorf("gops watch: unrecognized argument %q", args[i])
+		}
+	}
+	switch opts.sortBy {
+	case "rss", "cpu", "pid":
+	default:
+		return opts, fmt.Errorf("gops watch: --sort must be one of rss, cpu, pid, got %q", opts.sortBy)
+	}
+	return opts, nil
+}
+
+// watchedProcess tracks the last-seen usage of a process across ticks,
+// keyed by PID+start-time so that PID reuse doesn't create false deltas.
+type watchedProcess struct {
+	p         goprocess.P
+	startTime int64
+	rss       uint64
+	cpu       float64
+	changed   bool // rss or cpu moved by more than the change threshold this tick
+	newTicks  int  // ticks remaining to render this entry as "new"
+	goneTicks int  // ticks remaining to render this entry as "exited"
+}
+
+// runWatch implements `gops watch`: it clears the screen and re-renders the
+// process list at a fixed cadence, highlighting new, exited, and
+// significantly-changed processes.
+func runWatch(args []string) error {
+	opts, err := parseWatchArgs(args)
+	if err != nil {
+		return err
+	}
+
+	tracked := map[int]*watchedProcess{} // keyed by PID
+	ticker := time.NewTicker(opts.interval)
+	defer ticker.Stop()
+
+	for {
+		renderWatchTick(tracked, opts.sortBy)
+		<-ticker.C
+	}
+}
+
+func renderWatchTick(tracked map[int]*watchedProcess, sortBy string) {
+	ps := procSource.FindAll()
+	seen := map[int]bool{}
+
+	for _, p := range ps {
+		startTime := processStartTime(p.PID)
+		seen[p.PID] = true
+
+		prev, existed := tracked[p.PID]
+		rss, cpu := processUsage(p.PID)
+
+		switch {
+		case !existed || prev.startTime != startTime:
+			tracked[p.PID] = &watchedProcess{p: p, startTime: startTime, rss: rss, cpu: cpu, newTicks: 1}
+		default:
+			prev.changed = absDiffUint64(prev.rss, rss) > rssChangeThreshold || absDiff(prev.cpu, cpu) > cpuChangeThreshold
+			prev.p = p
+			prev.rss, prev.cpu = rss, cpu
+		}
+	}
+
+	// Age out processes that have exited, keeping them visible (in red) for
+	// exactly one more tick before dropping them.
+	for pid, w := range tracked {
+		if seen[pid] {
+			continue
+		}
+		if w.goneTicks == 0 {
+			w.goneTicks = 1
+		} else {
+			delete(tracked, pid)
+		}
+	}
+
+	entries := make([]*watchedProcess, 0, len(tracked))
+	for _, w := range tracked {
+		entries = append(entries, w)
+	}
+	sortWatched(entries, sortBy)
+
+	fmt.Print(ansiClear)
+	for _, w := range entries {
+		fmt.Println(renderWatchLine(w))
+		if w.newTicks > 0 {
+			w.newTicks--
+		}
+	}
+}
+
+func sortWatched(entries []*watchedProcess, sortBy string) {
+	sort.Slice(entries, func(i, j int) bool {
+		switch sortBy {
+		case "rss":
+			return entries[i].rss > entries[j].rss
+		case "cpu":
+			return entries[i].cpu > entries[j].cpu
+		default:
+			return entries[i].p.PID < entries[j].p.PID
+		}
+	})
+}
+
+func renderWatchLine(w *watchedProcess) string {
+	line := fmt.Sprintf("%s %s rss=%s cpu=%.1f%%", strconv.Itoa(w.p.PID), w.p.Exec, formatBytes(w.rss), w.cpu)
+	switch {
+	case w.goneTicks > 0:
+		return ansiRed + line + " (exited)" + ansiReset
+	case w.newTicks > 0:
+		return ansiGreen + line + " (new)" + ansiReset
+	case w.changed:
+		return ansiYellow + line + ansiReset
+	}
+	return line
+}
+
+func absDiff(a, b float64) float64 {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
+
+func absDiffUint64(a, b uint64) uint64 {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
+
+func processStartTime(pid int) int64 {
+	pp, err := process.NewProcess(int32(pid))
+	if err != nil {
+		return 0
+	}
+	t, err := pp.CreateTime()
+	if err != nil {
+		return 0
+	}
+	return t
+}
+
+func processUsage(pid int) (rss uint64, cpu float64) {
+	pp, err := process.NewProcess(int32(pid))
+	if err != nil {
+		return 0, 0
+	}
+	if v, err := pp.MemoryInfo(); err == nil {
+		rss = v.RSS
+	}
+	if v, err := pp.CPUPercent(); err == nil {
+		cpu = v
+	}
+	return rss, cpu
+}
@@ -0,0 +1,57 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"testing"
+
+	"github.com/google/gops/goprocess"
+)
+
+func TestSortWatched(t *testing.T) {
+	entries := []*watchedProcess{
+		{p: goprocess.P{PID: 3}, rss: 100, cpu: 5},
+		{p: goprocess.P{PID: 1}, rss: 300, cpu: 1},
+		{p: goprocess.P{PID: 2}, rss: 200, cpu: 9},
+	}
+
+	sortWatched(entries, "rss")
+	gotPIDs := pids(entries)
+	if want := []int{1, 2, 3}; !equalInts(gotPIDs, want) {
+		t.Errorf("sortWatched by rss = %v, want %v", gotPIDs, want)
+	}
+
+	sortWatched(entries, "cpu")
+	gotPIDs = pids(entries)
+	if want := []int{2, 3, 1}; !equalInts(gotPIDs, want) {
+		t.Errorf("sortWatched by cpu = %v, want %v", gotPIDs, want)
+	}
+
+	sortWatched(entries, "pid")
+	gotPIDs = pids(entries)
+	if want := []int{1, 2, 3}; !equalInts(gotPIDs, want) {
+		t.Errorf("sortWatched by pid = %v, want %v", gotPIDs, want)
+	}
+}
+
+func pids(entries []*watchedProcess) []int {
+	out := make([]int, len(entries))
+	for i, e := range entries {
+		out[i] = e.p.PID
+	}
+	return out
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}